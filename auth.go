@@ -0,0 +1,109 @@
+package haruhi
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Authenticator signs an outgoing request, e.g. attaching a bearer
+// token, an AWS SigV4 signature, or an OAuth2 access token.
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// Invalidator is implemented by Authenticators that cache credentials
+// and can be told to drop that cache, e.g. a TokenSource whose token
+// was rejected. RetryOn401Refresh uses this to force a genuine refetch
+// on its retry instead of resending the same stale credentials.
+type Invalidator interface {
+	Invalidate()
+}
+
+// basicAuth implements Authenticator on top of http.Request.SetBasicAuth,
+// so BasicAuth is just another Authenticator under the hood.
+type basicAuth struct {
+	username, password string
+}
+
+func (b basicAuth) Authenticate(req *http.Request) error {
+	req.SetBasicAuth(b.username, b.password)
+	return nil
+}
+
+// bearerAuth implements Authenticator with a static bearer token.
+type bearerAuth string
+
+func (b bearerAuth) Authenticate(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+string(b))
+	return nil
+}
+
+// BearerToken returns an Authenticator that sets a static
+// "Authorization: Bearer <token>" header.
+func BearerToken(token string) Authenticator {
+	return bearerAuth(token)
+}
+
+// tokenSourceAuth implements Authenticator on top of a user-supplied
+// function that fetches a bearer token, caching the result until
+// Invalidate is called -- e.g. by RetryOn401Refresh after a 401 --
+// since haruhi otherwise has no way to know a cached token has gone
+// stale.
+type tokenSourceAuth struct {
+	fn func(context.Context) (string, error)
+
+	mu    sync.Mutex
+	token string
+	have  bool
+}
+
+func (t *tokenSourceAuth) Authenticate(req *http.Request) error {
+	t.mu.Lock()
+	token, have := t.token, t.have
+	t.mu.Unlock()
+	if !have {
+		fresh, err := t.fn(req.Context())
+		if err != nil {
+			return errors.Wrap(err, "couldn't fetch token")
+		}
+		t.mu.Lock()
+		t.token, t.have = fresh, true
+		t.mu.Unlock()
+		token = fresh
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+func (t *tokenSourceAuth) Invalidate() {
+	t.mu.Lock()
+	t.have = false
+	t.mu.Unlock()
+}
+
+// TokenSource returns an Authenticator that calls fn to obtain a bearer
+// token, caching the result until it's invalidated. Pair it with
+// RetryOn401Refresh to have a 401 force a genuine refetch on retry.
+func TokenSource(fn func(context.Context) (string, error)) Authenticator {
+	return &tokenSourceAuth{fn: fn}
+}
+
+// RetryOn401Refresh returns a RetryOn predicate that retries exactly
+// once on a 401 response. Response() calls Invalidate on the request's
+// Authenticator (if it implements Invalidator, as TokenSource does)
+// before that retry, so a cached token is actually dropped and
+// refetched rather than resent as-is. Pair it with Retries(1) or
+// higher.
+func RetryOn401Refresh() func(*http.Response, error) bool {
+	retried := false
+	return func(resp *http.Response, err error) bool {
+		if retried || err != nil || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+			return false
+		}
+		retried = true
+		return true
+	}
+}
@@ -0,0 +1,100 @@
+package haruhi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestBearerTokenSetsAuthorizationHeader(t *testing.T) {
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		gotAuth = req.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	if _, err := URL(srv.URL).Auth(BearerToken("s3cr3t")).Get(); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if gotAuth != "Bearer s3cr3t" {
+		t.Errorf("Authorization = %q, want %q", gotAuth, "Bearer s3cr3t")
+	}
+}
+
+func TestBasicAuthIsImplementedAsAuthenticator(t *testing.T) {
+	var gotUser, gotPass string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		gotUser, gotPass, _ = req.BasicAuth()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	if _, err := URL(srv.URL).BasicAuth("kyon", "hunnyB").Get(); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if gotUser != "kyon" || gotPass != "hunnyB" {
+		t.Errorf("got user/pass %q/%q, want %q/%q", gotUser, gotPass, "kyon", "hunnyB")
+	}
+}
+
+// TestRetryOn401RefreshInvalidatesTokenSource guards against the bug
+// fixed in c2a2a6c: a 401 must actually invalidate the cached token so
+// the retry sends a freshly fetched one, not the same stale header.
+func TestRetryOn401RefreshInvalidatesTokenSource(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	var fetches int32
+	auth := TokenSource(func(ctx context.Context) (string, error) {
+		atomic.AddInt32(&fetches, 1)
+		return "token", nil
+	})
+
+	_, err := URL(srv.URL).
+		Auth(auth).
+		Retries(1).
+		RetryOn(RetryOn401Refresh()).
+		Get()
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("server got %d requests, want 2", got)
+	}
+	if got := atomic.LoadInt32(&fetches); got != 2 {
+		t.Fatalf("token source fetched %d times, want 2 (initial + refetch after invalidate)", got)
+	}
+}
+
+func TestTokenSourceCachesUntilInvalidated(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	var fetches int32
+	auth := TokenSource(func(ctx context.Context) (string, error) {
+		atomic.AddInt32(&fetches, 1)
+		return "token", nil
+	})
+
+	if _, err := URL(srv.URL).Auth(auth).Get(); err != nil {
+		t.Fatalf("first Get returned error: %v", err)
+	}
+	if _, err := URL(srv.URL).Auth(auth).Get(); err != nil {
+		t.Fatalf("second Get returned error: %v", err)
+	}
+	if got := atomic.LoadInt32(&fetches); got != 1 {
+		t.Fatalf("token source fetched %d times, want 1 (cached across requests)", got)
+	}
+}
@@ -0,0 +1,103 @@
+package haruhi
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+	"time"
+)
+
+// defaultDebugBodyLimit is the max body size (in bytes) included in a
+// dump before falling back to body=false, when DebugBodyLimit hasn't
+// been called.
+const defaultDebugBodyLimit = 4096
+
+// debugWriter is the package-wide debug sink set by SetDebug, used by
+// requests that haven't called Debug themselves.
+var debugWriter io.Writer
+
+// SetDebug enables request/response dumping for every request that
+// hasn't called Debug itself, writing the dumps to w. Pass nil to
+// disable it again.
+func SetDebug(w io.Writer) {
+	debugWriter = w
+}
+
+// Debug enables request/response dumping for this request only,
+// writing to w and overriding the package-wide writer set by SetDebug.
+func (r *Request) Debug(w io.Writer) *Request {
+	r.debug = w
+	return r
+}
+
+// DebugBodyLimit sets the max body size included in a dump before
+// falling back to body=false, defaults to defaultDebugBodyLimit.
+func (r *Request) DebugBodyLimit(n int) *Request {
+	r.debugBodyLimit = n
+	return r
+}
+
+// debugSink returns the writer to dump to, or nil if dumping is
+// disabled for this request.
+func (r *Request) debugSink() io.Writer {
+	if r.debug != nil {
+		return r.debug
+	}
+	return debugWriter
+}
+
+// dumpRequest writes a request dump to the debug sink, if enabled.
+// Mirrors the common AWS SDK-style debug handler: multipart or
+// oversized bodies are left out of the dump. Deliberately uses
+// r.bodyKnownLength rather than req.ContentLength: the latter is only
+// populated by net/http for a handful of concrete reader types and
+// silently reads 0 (not -1) for any other streaming io.Reader, which
+// would otherwise let a large custom-reader upload slip through and
+// get buffered whole by httputil.DumpRequestOut.
+func (r *Request) dumpRequest(req *http.Request) {
+	w := r.debugSink()
+	if w == nil {
+		return
+	}
+	dump, err := httputil.DumpRequestOut(req, !r.tooBigToDump(req.Header.Get("Content-Type"), r.bodyKnownLength))
+	if err != nil {
+		logger.Println("haruhi debug: couldn't dump request:", err.Error())
+		return
+	}
+	fmt.Fprintf(w, "--- haruhi request: %s ---\n%s\n", req.URL.String(), dump)
+}
+
+// dumpResponse writes a response dump to the debug sink, if enabled,
+// including the wall-clock duration of the call.
+func (r *Request) dumpResponse(resp *http.Response, duration time.Duration) {
+	w := r.debugSink()
+	if w == nil || resp == nil {
+		return
+	}
+	dump, err := httputil.DumpResponse(resp, !r.tooBigToDump(resp.Header.Get("Content-Type"), resp.ContentLength))
+	if err != nil {
+		logger.Println("haruhi debug: couldn't dump response:", err.Error())
+		return
+	}
+	url := ""
+	if resp.Request != nil {
+		url = resp.Request.URL.String()
+	}
+	fmt.Fprintf(w, "--- haruhi response: %s (%s) ---\n%s\n", url, duration, dump)
+}
+
+// tooBigToDump reports whether a body should be left out of a dump:
+// true for multipart content, or a body that's of unknown/streaming
+// length or over the configured limit.
+func (r *Request) tooBigToDump(contentType string, contentLength int64) bool {
+	if strings.HasPrefix(contentType, "multipart/") {
+		return true
+	}
+	limit := r.debugBodyLimit
+	if limit <= 0 {
+		limit = defaultDebugBodyLimit
+	}
+	return contentLength < 0 || contentLength > int64(limit)
+}
@@ -1,6 +1,9 @@
 package haruhi
 
-import "log"
+import (
+	"log"
+	"net/http"
+)
 
 var (
 	// logger to use in haruhi for reporting errors.
@@ -9,6 +12,16 @@ var (
 	// shouldPanic tells haruhi to panic if intermediate
 	// builders return an error.
 	shouldPanic = false
+
+	// DefaultOnRequest hooks are copied onto every new Request built by
+	// URL(...), run in order after the *http.Request is built and
+	// before it's sent.
+	DefaultOnRequest []func(*http.Request) error
+
+	// DefaultOnResponse hooks are copied onto every new Request built
+	// by URL(...), run in order after the status-code/expectation
+	// logic in Response().
+	DefaultOnResponse []func(*http.Response) error
 )
 
 func init() {
@@ -0,0 +1,84 @@
+package haruhi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOnRequestAndOnResponseRunInOrder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	var order []string
+
+	_, err := URL(srv.URL).
+		OnRequest(func(req *http.Request) error {
+			order = append(order, "request-1")
+			return nil
+		}).
+		OnRequest(func(req *http.Request) error {
+			order = append(order, "request-2")
+			return nil
+		}).
+		OnResponse(func(resp *http.Response) error {
+			order = append(order, "response-1")
+			return nil
+		}).
+		OnResponse(func(resp *http.Response) error {
+			order = append(order, "response-2")
+			return nil
+		}).
+		Get()
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+
+	want := []string{"request-1", "request-2", "response-1", "response-2"}
+	if len(order) != len(want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("order = %v, want %v", order, want)
+		}
+	}
+}
+
+func TestOnRequestErrorShortCircuitsBeforeSending(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	boom := errors.New("boom")
+	_, err := URL(srv.URL).
+		OnRequest(func(req *http.Request) error { return boom }).
+		Get()
+	if err == nil {
+		t.Fatal("expected an error from the OnRequest hook")
+	}
+	if called {
+		t.Fatal("server should not have been called after OnRequest failed")
+	}
+}
+
+func TestOnResponseErrorPropagates(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	boom := errors.New("boom")
+	_, err := URL(srv.URL).
+		OnResponse(func(resp *http.Response) error { return boom }).
+		Get()
+	if err == nil {
+		t.Fatal("expected an error from the OnResponse hook")
+	}
+}
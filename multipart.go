@@ -0,0 +1,76 @@
+package haruhi
+
+import (
+	"context"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// BodyMultipart builds a `multipart/form-data` body by handing a
+// `*multipart.Writer` to fn. The body is streamed through an `io.Pipe`
+// so large files aren't buffered in memory, and the `Content-Type`
+// header (including the boundary) is set from the writer. If fn returns
+// an error, it is propagated by cancelling the request's context (a
+// child context created here, not shared with any other goroutine) so
+// `Request()`/`Response()` fail cleanly instead of hanging.
+func (r *Request) BodyMultipart(fn func(*multipart.Writer) error) *Request {
+	if !shouldSetOrPanic(fn, "multipart callback") {
+		return r
+	}
+	ctx, cancel := context.WithCancel(r.ctx)
+	r.ctx = ctx
+
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	r.headers.Set("Content-Type", writer.FormDataContentType())
+
+	go func() {
+		err := fn(writer)
+		if err == nil {
+			err = writer.Close()
+		}
+		if err != nil {
+			err = errors.Wrap(err, "haruhi failed to write multipart body")
+			logger.Println(err.Error())
+			cancel()
+			pw.CloseWithError(err)
+			return
+		}
+		pw.Close()
+	}()
+
+	return r.Body(pr)
+}
+
+// FormField writes a plain form field into writer, for use inside a
+// `BodyMultipart` callback.
+func FormField(writer *multipart.Writer, name, value string) error {
+	return errors.Wrap(writer.WriteField(name, value), "couldn't write form field")
+}
+
+// FormFile streams the contents of src into a new file part named
+// fieldName, for use inside a `BodyMultipart` callback.
+func FormFile(writer *multipart.Writer, fieldName, fileName string, src io.Reader) error {
+	part, err := writer.CreateFormFile(fieldName, fileName)
+	if err != nil {
+		return errors.Wrap(err, "couldn't create form file part")
+	}
+	_, err = io.Copy(part, src)
+	return errors.Wrap(err, "couldn't stream form file part")
+}
+
+// FormFilePath opens the file at path and streams it into a new file
+// part named fieldName, using the file's base name, for use inside a
+// `BodyMultipart` callback.
+func FormFilePath(writer *multipart.Writer, fieldName, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "couldn't open file for multipart upload")
+	}
+	defer f.Close()
+	return FormFile(writer, fieldName, filepath.Base(path), f)
+}
@@ -0,0 +1,72 @@
+package haruhi
+
+import (
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBodyMultipartSendsFieldsAndFiles(t *testing.T) {
+	var gotField, gotFileName, gotFileContent string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if err := req.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("ParseMultipartForm: %v", err)
+			return
+		}
+		gotField = req.FormValue("name")
+		file, header, err := req.FormFile("upload")
+		if err != nil {
+			t.Errorf("FormFile: %v", err)
+			return
+		}
+		defer file.Close()
+		gotFileName = header.Filename
+		data, _ := io.ReadAll(file)
+		gotFileContent = string(data)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	_, err := URL(srv.URL).BodyMultipart(func(w *multipart.Writer) error {
+		if err := FormField(w, "name", "haruhi"); err != nil {
+			return err
+		}
+		return FormFile(w, "upload", "greeting.txt", strings.NewReader("hello, world"))
+	}).Post()
+	if err != nil {
+		t.Fatalf("Post returned error: %v", err)
+	}
+
+	if gotField != "haruhi" {
+		t.Errorf("field = %q, want %q", gotField, "haruhi")
+	}
+	if gotFileName != "greeting.txt" {
+		t.Errorf("file name = %q, want %q", gotFileName, "greeting.txt")
+	}
+	if gotFileContent != "hello, world" {
+		t.Errorf("file content = %q, want %q", gotFileContent, "hello, world")
+	}
+}
+
+// TestBodyMultipartCallbackErrorFailsRequest guards against the race
+// fixed in 37d86a2: a failing callback must reliably fail the request
+// instead of hanging or racing on shared state.
+func TestBodyMultipartCallbackErrorFailsRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	boom := errors.New("boom")
+	_, err := URL(srv.URL).BodyMultipart(func(w *multipart.Writer) error {
+		return boom
+	}).Post()
+	if err == nil {
+		t.Fatal("expected an error when the multipart callback fails, got nil")
+	}
+}
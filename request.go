@@ -43,14 +43,47 @@ type Request struct {
 	path string
 	// Method to use for HTTP request, defaults to "GET".
 	method string
-	// Username for basic auth.
-	username string
-	// Password for basic auth.
-	password string
+	// auth signs the request, defaults to nil (no signing). Set via
+	// Auth, or BasicAuth which is implemented on top of it.
+	auth Authenticator
 	// statusExpectation is the status code we expect to get.
 	statusExpectation int
 	// Timeout for the request, defaults to 0 (meaning no timeout).
 	timeout time.Duration
+	// bodyFactory re-creates the body reader from scratch, set by the
+	// Body... helpers that hold onto the full content (BodyBytes,
+	// BodyString, BodyJson) so a failed request can be retried with a
+	// fresh, unread body.
+	bodyFactory func() io.Reader
+	// retries is the maximum number of times to retry a failed request.
+	retries uint
+	// retryBase and retryMax bound the exponential backoff delay used
+	// between retries, defaults to defaultRetryBase/defaultRetryMax.
+	retryBase, retryMax time.Duration
+	// retryOn decides whether a given response/error should be retried,
+	// defaults to defaultRetryOn.
+	retryOn func(*http.Response, error) bool
+	// onRequest hooks run in order, after the *http.Request is built
+	// and before it's sent. Defaults to a copy of DefaultOnRequest.
+	onRequest []func(*http.Request) error
+	// onResponse hooks run in order, after the status-code/expectation
+	// logic in Response(). Defaults to a copy of DefaultOnResponse.
+	onResponse []func(*http.Response) error
+	// debug is the per-request debug sink set by Debug, defaults to
+	// nil (falls back to the package-wide debugWriter set by SetDebug).
+	debug io.Writer
+	// debugBodyLimit caps how large a body can be before a debug dump
+	// leaves it out, defaults to defaultDebugBodyLimit.
+	debugBodyLimit int
+	// bodyKnownLength is the exact byte length of the request body when
+	// haruhi built it from something bounded (BodyBytes, BodyString,
+	// BodyJson, BodyXML), or -1 for any other Body(io.Reader) call,
+	// whose true size haruhi can't know. http.Request.ContentLength
+	// can't be trusted for this: it's only populated for a handful of
+	// concrete reader types, silently staying 0 (not -1) for a custom
+	// streaming reader, which would otherwise fool a debug dump into
+	// buffering it whole.
+	bodyKnownLength int64
 }
 
 // URL will start building a request with the given URL (scheme+domain),
@@ -64,6 +97,9 @@ func URL(what string) *Request {
 		headers:            http.Header{},
 		params:             url.Values{},
 		statusCodeHandlers: map[int]func(*http.Response) error{},
+		onRequest:          append([]func(*http.Request) error{}, DefaultOnRequest...),
+		onResponse:         append([]func(*http.Response) error{}, DefaultOnResponse...),
+		bodyKnownLength:    0,
 	}
 }
 
@@ -137,20 +173,33 @@ func (r *Request) Header(name, value string) *Request {
 	return r
 }
 
-// Body tells us we need to read the body request from the reader.
+// Body tells us we need to read the body request from the reader. An
+// arbitrary reader cannot be replayed or sized up front, so this clears
+// any bodyFactory set by an earlier Body... call and marks the body
+// length unknown for debug dumping; use BodyBytes/BodyString/BodyJson
+// if the body needs to survive a retry, or any of those or BodyXML if
+// it needs to be safely dumped.
 func (r *Request) Body(body io.Reader) *Request {
 	r.body = body
+	r.bodyFactory = nil
+	r.bodyKnownLength = -1
 	return r
 }
 
 // BodyBytes will use slice of bytes as body.
 func (r *Request) BodyBytes(body []byte) *Request {
-	return r.Body(bytes.NewReader(body))
+	r.Body(bytes.NewReader(body))
+	r.bodyFactory = func() io.Reader { return bytes.NewReader(body) }
+	r.bodyKnownLength = int64(len(body))
+	return r
 }
 
 // BodyString will use string as body.
 func (r *Request) BodyString(body string) *Request {
-	return r.Body(strings.NewReader(body))
+	r.Body(strings.NewReader(body))
+	r.bodyFactory = func() io.Reader { return strings.NewReader(body) }
+	r.bodyKnownLength = int64(len(body))
+	return r
 }
 
 // ErrorHandler will set the error handler to be called if the request
@@ -182,11 +231,64 @@ func (r *Request) IfNotExpectedStatusCode(statusCode int, handler func(*http.Res
 	return r
 }
 
-// BasicAuth sets the request's Authorization header to use HTTP
-// Basic Authentication with the provided username and password.
+// BasicAuth sets the request's Authorization header to use HTTP Basic
+// Authentication with the provided username and password, implemented
+// on top of the Authenticator mechanism.
 func (r *Request) BasicAuth(username, password string) *Request {
-	r.username = username
-	r.password = password
+	return r.Auth(basicAuth{username, password})
+}
+
+// Auth sets the Authenticator used to sign this request (e.g. a bearer
+// token, AWS SigV4, or OAuth2 token source), invoked in Request() after
+// headers are merged but before the request is otherwise finalised.
+func (r *Request) Auth(auth Authenticator) *Request {
+	if shouldSetOrPanic(auth, "authenticator") {
+		r.auth = auth
+	}
+	return r
+}
+
+// Retries sets the maximum number of times to retry a failed request,
+// defaults to 0 (no retries).
+func (r *Request) Retries(n uint) *Request {
+	r.retries = n
+	return r
+}
+
+// RetryBackoff sets the base and max delay used for exponential backoff
+// between retries, defaults to defaultRetryBase/defaultRetryMax.
+func (r *Request) RetryBackoff(base, max time.Duration) *Request {
+	r.retryBase = base
+	r.retryMax = max
+	return r
+}
+
+// RetryOn overrides the default retry predicate (network error, 5xx, or
+// 429) with a custom one.
+func (r *Request) RetryOn(on func(*http.Response, error) bool) *Request {
+	if shouldSetOrPanic(on, "retry predicate") {
+		r.retryOn = on
+	}
+	return r
+}
+
+// OnRequest appends a hook that runs, in order, after the *http.Request
+// is built but before it's sent. An error short-circuits Response() and
+// flows through the existing errorHandler.
+func (r *Request) OnRequest(hook func(*http.Request) error) *Request {
+	if shouldSetOrPanic(hook, "request hook") {
+		r.onRequest = append(r.onRequest, hook)
+	}
+	return r
+}
+
+// OnResponse appends a hook that runs, in order, after the status-code
+// and expectation logic in Response(). An error short-circuits
+// Response() and flows through the existing errorHandler.
+func (r *Request) OnResponse(hook func(*http.Response) error) *Request {
+	if shouldSetOrPanic(hook, "response hook") {
+		r.onResponse = append(r.onResponse, hook)
+	}
 	return r
 }
 
@@ -205,7 +307,11 @@ func (r *Request) BodyJson(body any) *Request {
 		logger.Println("leaving body empty:", err.Error())
 		return r
 	}
-	return r.Body(buf)
+	data := buf.Bytes()
+	r.Body(bytes.NewReader(data))
+	r.bodyFactory = func() io.Reader { return bytes.NewReader(data) }
+	r.bodyKnownLength = int64(len(data))
+	return r
 }
 
 // BodyXML will encode given interfact/instance into XML and use that as body.
@@ -223,7 +329,10 @@ func (r *Request) BodyXML(body any) *Request {
 		logger.Println("leaving body empty:", err.Error())
 		return r
 	}
-	return r.Body(buf)
+	data := buf.Bytes()
+	r.Body(bytes.NewReader(data))
+	r.bodyKnownLength = int64(len(data))
+	return r
 }
 
 // BodyFormData will take values and send them as formdata.
@@ -259,8 +368,10 @@ func (r *Request) Request() (*http.Request, context.CancelFunc, error) {
 	}
 	mergeHeaders(req.Header, r.headers, true)
 
-	if len(r.username) > 0 || len(r.password) > 0 {
-		req.SetBasicAuth(r.username, r.password)
+	if r.auth != nil {
+		if err := r.auth.Authenticate(req); err != nil {
+			return req, cancel, errors.Wrap(err, "haruhi failed to authenticate request")
+		}
 	}
 
 	q := req.URL.Query()
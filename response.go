@@ -1,6 +1,7 @@
 package haruhi
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -8,6 +9,7 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"time"
 )
 
 // Make a blocking GET request and return the response as string.
@@ -36,12 +38,67 @@ func (r *Request) Delete() (string, error) {
 
 // Make a non-blocking request and get the response object with cancel.
 func (r *Request) Response() (resp *http.Response, cancel context.CancelFunc, err error) {
+	retryOn := r.retryOn
+	if retryOn == nil {
+		retryOn = defaultRetryOn
+	}
+	origCtx := r.ctx
+
 	var req *http.Request
-	req, cancel, err = r.Request()
-	if err != nil {
-		return
+	for attempt := uint(0); ; attempt++ {
+		if attempt > 0 {
+			r.ctx = origCtx
+			if r.bodyFactory != nil {
+				r.body = r.bodyFactory()
+			}
+		}
+
+		req, cancel, err = r.Request()
+		if err != nil {
+			return
+		}
+		for _, hook := range r.onRequest {
+			if err = hook(req); err != nil {
+				if r.errorHandler != nil {
+					err = r.errorHandler(nil, err)
+				}
+				return
+			}
+		}
+		r.dumpRequest(req)
+		start := time.Now()
+		resp, err = r.client.Do(req)
+		r.dumpResponse(resp, time.Since(start))
+
+		if attempt >= r.retries || !retryOn(resp, err) {
+			break
+		}
+
+		// We're committed to retrying (or aborting because we can't):
+		// release this attempt's response/connection before doing
+		// anything else, so every exit from here on leaves nothing
+		// dangling.
+		if resp != nil {
+			resp.Body.Close()
+		}
+		cancel()
+
+		if r.body != nil && r.bodyFactory == nil {
+			err = errors.New("haruhi: request has a non-replayable body, set it with BodyBytes/BodyString/BodyJson to allow retries")
+			return
+		}
+		if resp != nil && resp.StatusCode == http.StatusUnauthorized {
+			if invalidator, ok := r.auth.(Invalidator); ok {
+				invalidator.Invalidate()
+			}
+		}
+
+		wait := r.retryDelay(resp, attempt)
+		if sleepErr := sleepContext(origCtx, wait); sleepErr != nil {
+			err = sleepErr
+			return
+		}
 	}
-	resp, err = r.client.Do(req)
 
 	// Call the error handler if it has been set.
 	if err != nil {
@@ -65,6 +122,14 @@ func (r *Request) Response() (resp *http.Response, cancel context.CancelFunc, er
 		err = r.ifNotStatusCodeHandler(resp)
 		return
 	}
+	for _, hook := range r.onResponse {
+		if err = hook(resp); err != nil {
+			if r.errorHandler != nil {
+				err = r.errorHandler(resp, err)
+			}
+			return
+		}
+	}
 	return
 }
 
@@ -132,3 +197,51 @@ func (r *Request) ResponseXML(v any) error {
 	defer body.Close()
 	return xml.NewDecoder(body).Decode(v)
 }
+
+// ResponseJsonStream hands a json.Decoder to fn while the body is still
+// open, for long-lived responses carrying more than one JSON document.
+// The body is closed once fn returns.
+func (r *Request) ResponseJsonStream(fn func(dec *json.Decoder) error) error {
+	body, cancel, err := r.ResponseBody()
+	defer cancel()
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	return fn(json.NewDecoder(body))
+}
+
+// ResponseXMLStream hands an xml.Decoder to fn while the body is still
+// open, for long-lived responses carrying more than one XML document.
+// The body is closed once fn returns.
+func (r *Request) ResponseXMLStream(fn func(dec *xml.Decoder) error) error {
+	body, cancel, err := r.ResponseBody()
+	defer cancel()
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	return fn(xml.NewDecoder(body))
+}
+
+// ResponseLines calls fn with each line of the body as it arrives,
+// using a bufio.Scanner. Cancelling the request's context stops the
+// stream mid-read.
+func (r *Request) ResponseLines(fn func(line []byte) error) error {
+	body, cancel, err := r.ResponseBody()
+	defer cancel()
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		if err := r.ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(scanner.Bytes()); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,102 @@
+package haruhi
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// defaultRetryBase is the base delay used for exponential backoff
+	// when RetryBackoff hasn't been called.
+	defaultRetryBase = 200 * time.Millisecond
+	// defaultRetryMax is the max delay used for exponential backoff
+	// when RetryBackoff hasn't been called.
+	defaultRetryMax = 5 * time.Second
+)
+
+// defaultRetryOn is used when RetryOn hasn't been set: retry on a
+// network error, a 429, or any 5xx status code.
+func defaultRetryOn(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	if resp == nil {
+		return false
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// retryDelay decides how long to wait before the next attempt. It
+// honours a Retry-After header on 429/503 responses, otherwise it falls
+// back to exponential backoff with jitter:
+// delay = min(max, base * 2^attempt) * (0.5 + rand*0.5).
+func (r *Request) retryDelay(resp *http.Response, attempt uint) time.Duration {
+	if resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return delay
+		}
+	}
+
+	base, max := r.retryBase, r.retryMax
+	if base <= 0 {
+		base = defaultRetryBase
+	}
+	if max <= 0 {
+		max = defaultRetryMax
+	}
+
+	delay := base
+	if delay > max {
+		delay = max
+	}
+	for i := uint(0); i < attempt; i++ {
+		delay *= 2
+		if delay <= 0 || delay > max {
+			delay = max
+			break
+		}
+	}
+
+	jitter := 0.5 + rand.Float64()*0.5
+	return time.Duration(float64(delay) * jitter)
+}
+
+// parseRetryAfter parses a Retry-After header in either the
+// delay-seconds or HTTP-date form.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(when); delay > 0 {
+			return delay, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// sleepContext sleeps for d, returning early with ctx.Err() if ctx is
+// cancelled first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,64 @@
+package haruhi
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	future := time.Now().Add(90 * time.Second).UTC().Format(http.TimeFormat)
+	past := time.Now().Add(-90 * time.Second).UTC().Format(http.TimeFormat)
+
+	cases := []struct {
+		name    string
+		value   string
+		wantOK  bool
+		wantMin time.Duration
+		wantMax time.Duration
+	}{
+		{"seconds", "120", true, 120 * time.Second, 120 * time.Second},
+		{"zero seconds", "0", true, 0, 0},
+		{"negative seconds", "-5", false, 0, 0},
+		{"http date in future", future, true, 80 * time.Second, 100 * time.Second},
+		{"http date in past", past, true, 0, 0},
+		{"garbage", "not-a-time", false, 0, 0},
+		{"empty", "", false, 0, 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tc.value)
+			if ok != tc.wantOK {
+				t.Fatalf("parseRetryAfter(%q) ok = %v, want %v", tc.value, ok, tc.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if got < tc.wantMin || got > tc.wantMax {
+				t.Fatalf("parseRetryAfter(%q) = %v, want between %v and %v", tc.value, got, tc.wantMin, tc.wantMax)
+			}
+		})
+	}
+}
+
+func TestRetryDelayUsesRetryAfterHeader(t *testing.T) {
+	r := URL("https://example.com")
+	resp := &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{"Retry-After": []string{"7"}},
+	}
+	if got := r.retryDelay(resp, 0); got != 7*time.Second {
+		t.Fatalf("retryDelay = %v, want %v", got, 7*time.Second)
+	}
+}
+
+func TestRetryDelayExponentialBackoffIsBounded(t *testing.T) {
+	r := URL("https://example.com").RetryBackoff(100*time.Millisecond, time.Second)
+	for attempt := uint(0); attempt < 6; attempt++ {
+		got := r.retryDelay(nil, attempt)
+		if got < 0 || got > time.Second {
+			t.Fatalf("retryDelay(attempt=%d) = %v, want between 0 and %v", attempt, got, time.Second)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package haruhi
+
+import (
+	"bufio"
+	"strconv"
+	"strings"
+)
+
+// SSEEvent is a single Server-Sent Event parsed from a text/event-stream
+// response, per the WHATWG spec.
+type SSEEvent struct {
+	// Event is the event type, from the last "event:" field seen since
+	// the previous dispatch, defaults to "message" if not set.
+	Event string
+	// Data is the concatenation of all "data:" lines since the
+	// previous dispatch, joined by "\n".
+	Data string
+	// ID is the last "id:" field seen, carried across events.
+	ID string
+	// Retry is the reconnection time in milliseconds, from the last
+	// "retry:" field seen, carried across events; 0 if never set.
+	Retry int
+}
+
+// ResponseSSE parses the body as a text/event-stream, per the WHATWG
+// spec: "data:" lines accumulate until a blank line dispatches the
+// event, "event:" sets the type of the next dispatched event only,
+// "id:"/"retry:" fields are carried across events, and lines starting
+// with ":" are ignored as comments. fn is called for
+// each dispatched event. Cancelling the request's context stops the
+// stream mid-read.
+func (r *Request) ResponseSSE(fn func(event SSEEvent) error) error {
+	body, cancel, err := r.ResponseBody()
+	defer cancel()
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	var data []string
+	var eventType, id string
+	var retry int
+
+	dispatch := func() error {
+		defer func() { data, eventType = nil, "" }()
+		if len(data) == 0 {
+			return nil
+		}
+		event := SSEEvent{Event: eventType, Data: strings.Join(data, "\n"), ID: id, Retry: retry}
+		if event.Event == "" {
+			event.Event = "message"
+		}
+		return fn(event)
+	}
+
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		if err := r.ctx.Err(); err != nil {
+			return err
+		}
+		line := scanner.Text()
+		switch field, value, _ := strings.Cut(line, ":"); {
+		case line == "":
+			if err := dispatch(); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, ":"):
+			// Comment, ignore.
+		case field == "data":
+			data = append(data, strings.TrimPrefix(value, " "))
+		case field == "event":
+			eventType = strings.TrimPrefix(value, " ")
+		case field == "id":
+			id = strings.TrimPrefix(value, " ")
+		case field == "retry":
+			if n, err := strconv.Atoi(strings.TrimPrefix(value, " ")); err == nil {
+				retry = n
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return dispatch()
+}
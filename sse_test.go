@@ -0,0 +1,73 @@
+package haruhi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResponseSSE(t *testing.T) {
+	body := "event: greeting\n" +
+		"data: hello\n" +
+		"data: world\n" +
+		"id: 1\n" +
+		"retry: 5000\n" +
+		"\n" +
+		": this is a comment\n" +
+		"data: second\n" +
+		"\n" +
+		"data: third\n" +
+		"\n"
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	var events []SSEEvent
+	err := URL(srv.URL).ResponseSSE(func(e SSEEvent) error {
+		events = append(events, e)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ResponseSSE returned error: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3", len(events))
+	}
+
+	first := events[0]
+	if first.Event != "greeting" {
+		t.Errorf("events[0].Event = %q, want %q", first.Event, "greeting")
+	}
+	if first.Data != "hello\nworld" {
+		t.Errorf("events[0].Data = %q, want %q", first.Data, "hello\nworld")
+	}
+	if first.ID != "1" {
+		t.Errorf("events[0].ID = %q, want %q", first.ID, "1")
+	}
+	if first.Retry != 5000 {
+		t.Errorf("events[0].Retry = %d, want %d", first.Retry, 5000)
+	}
+
+	second := events[1]
+	if second.Event != "message" {
+		t.Errorf("events[1].Event = %q, want default %q", second.Event, "message")
+	}
+	if second.Data != "second" {
+		t.Errorf("events[1].Data = %q, want %q", second.Data, "second")
+	}
+	if second.ID != "1" {
+		t.Errorf("events[1].ID = %q, want carried-over %q", second.ID, "1")
+	}
+	if second.Retry != 5000 {
+		t.Errorf("events[1].Retry = %d, want carried-over %d", second.Retry, 5000)
+	}
+
+	third := events[2]
+	if third.Data != "third" {
+		t.Errorf("events[2].Data = %q, want %q", third.Data, "third")
+	}
+}